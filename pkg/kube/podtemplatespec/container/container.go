@@ -0,0 +1,171 @@
+package container
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const notFound = -1
+
+// Modification is a function which mutates a corev1.Container. It is the container-level
+// counterpart to podtemplatespec.Modification.
+type Modification func(*corev1.Container)
+
+func New(modifications ...Modification) corev1.Container {
+	c := corev1.Container{}
+	for _, mod := range modifications {
+		mod(&c)
+	}
+	return c
+}
+
+func Apply(modifications ...Modification) Modification {
+	return func(c *corev1.Container) {
+		for _, mod := range modifications {
+			mod(c)
+		}
+	}
+}
+
+// WithEnvVar merges envVar into the container's env list by name: an existing env var with the
+// same name is replaced in place, otherwise envVar is appended.
+func WithEnvVar(envVar corev1.EnvVar) Modification {
+	return func(c *corev1.Container) {
+		idx := findEnvVarIndexByName(envVar.Name, c.Env)
+		if idx == notFound {
+			c.Env = append(c.Env, envVar)
+			return
+		}
+		c.Env[idx] = envVar
+	}
+}
+
+// WithEnvFromSecret merges an env var named envVarName into the container, sourcing its value
+// from secretKey in the Secret secretName.
+func WithEnvFromSecret(envVarName, secretName, secretKey string) Modification {
+	return WithEnvVar(corev1.EnvVar{
+		Name: envVarName,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  secretKey,
+			},
+		},
+	})
+}
+
+// WithEnvFromConfigMap merges an env var named envVarName into the container, sourcing its
+// value from configMapKey in the ConfigMap configMapName.
+func WithEnvFromConfigMap(envVarName, configMapName, configMapKey string) Modification {
+	return WithEnvVar(corev1.EnvVar{
+		Name: envVarName,
+		ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+				Key:                  configMapKey,
+			},
+		},
+	})
+}
+
+// WithVolumeMount merges mount into the container's volume mounts by mount path: an existing
+// mount at the same path is replaced in place, otherwise mount is appended.
+func WithVolumeMount(mount corev1.VolumeMount) Modification {
+	return func(c *corev1.Container) {
+		idx := findVolumeMountIndexByPath(mount.MountPath, c.VolumeMounts)
+		if idx == notFound {
+			c.VolumeMounts = append(c.VolumeMounts, mount)
+			return
+		}
+		c.VolumeMounts[idx] = mount
+	}
+}
+
+func WithReadinessProbe(probe *corev1.Probe) Modification {
+	return func(c *corev1.Container) {
+		c.ReadinessProbe = probe
+	}
+}
+
+func WithLivenessProbe(probe *corev1.Probe) Modification {
+	return func(c *corev1.Container) {
+		c.LivenessProbe = probe
+	}
+}
+
+func WithStartupProbe(probe *corev1.Probe) Modification {
+	return func(c *corev1.Container) {
+		c.StartupProbe = probe
+	}
+}
+
+// WithResourceRequests merges requests into the container's resource requests by resource name.
+func WithResourceRequests(requests corev1.ResourceList) Modification {
+	return func(c *corev1.Container) {
+		if c.Resources.Requests == nil {
+			c.Resources.Requests = corev1.ResourceList{}
+		}
+		for name, quantity := range requests {
+			c.Resources.Requests[name] = quantity
+		}
+	}
+}
+
+// WithResourceLimits merges limits into the container's resource limits by resource name.
+func WithResourceLimits(limits corev1.ResourceList) Modification {
+	return func(c *corev1.Container) {
+		if c.Resources.Limits == nil {
+			c.Resources.Limits = corev1.ResourceList{}
+		}
+		for name, quantity := range limits {
+			c.Resources.Limits[name] = quantity
+		}
+	}
+}
+
+func WithSecurityContext(securityContext *corev1.SecurityContext) Modification {
+	return func(c *corev1.Container) {
+		c.SecurityContext = securityContext
+	}
+}
+
+func WithCommand(command []string) Modification {
+	return func(c *corev1.Container) {
+		c.Command = command
+	}
+}
+
+func WithArgs(args []string) Modification {
+	return func(c *corev1.Container) {
+		c.Args = args
+	}
+}
+
+func WithImage(image string) Modification {
+	return func(c *corev1.Container) {
+		c.Image = image
+	}
+}
+
+func WithImagePullPolicy(policy corev1.PullPolicy) Modification {
+	return func(c *corev1.Container) {
+		c.ImagePullPolicy = policy
+	}
+}
+
+func findEnvVarIndexByName(name string, envVars []corev1.EnvVar) int {
+	for idx, e := range envVars {
+		if e.Name == name {
+			return idx
+		}
+	}
+	return notFound
+}
+
+func findVolumeMountIndexByPath(mountPath string, mounts []corev1.VolumeMount) int {
+	for idx, m := range mounts {
+		if m.MountPath == mountPath {
+			return idx
+		}
+	}
+	return notFound
+}
@@ -1,8 +1,14 @@
 package podtemplatespec
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/mongodb/mongodb-kubernetes-operator/pkg/kube/podtemplatespec/container"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 )
 
 type Modification func(*corev1.PodTemplateSpec)
@@ -44,6 +50,14 @@ func WithContainer(name string, containerfunc func(*corev1.Container)) Modificat
 	}
 }
 
+// WithContainerModifications applies container-level Modifications (see the sibling `container`
+// package: WithEnvVar, WithVolumeMount, WithResourceRequests, etc.) to the named container,
+// merging idempotently by their respective keys instead of requiring the caller to hand-roll an
+// ad-hoc mutation closure that re-appends on every reconcile.
+func WithContainerModifications(name string, mods ...container.Modification) Modification {
+	return WithContainer(name, container.Apply(mods...))
+}
+
 func WithContainerByIndex(index int, funcs ...func(container *corev1.Container)) func(podTemplateSpec *corev1.PodTemplateSpec) {
 	return func(podTemplateSpec *corev1.PodTemplateSpec) {
 		if index >= len(podTemplateSpec.Spec.Containers) {
@@ -163,6 +177,45 @@ func WithAffinity(stsName, antiAffinityLabelKey string, weight int) Modification
 	}
 }
 
+// WithPreferredPodAntiAffinity appends a weighted PodAffinityTerm to the PodTemplateSpec's
+// PreferredDuringSchedulingIgnoredDuringExecution pod anti-affinity terms, lazily initializing
+// Affinity and PodAntiAffinity if they have not yet been set.
+func WithPreferredPodAntiAffinity(weight int32, term corev1.PodAffinityTerm) Modification {
+	return func(podTemplateSpec *corev1.PodTemplateSpec) {
+		podAntiAffinity := ensurePodAntiAffinity(podTemplateSpec)
+		podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			corev1.WeightedPodAffinityTerm{
+				Weight:          weight,
+				PodAffinityTerm: term,
+			},
+		)
+	}
+}
+
+// WithRequiredPodAntiAffinity appends a PodAffinityTerm to the PodTemplateSpec's
+// RequiredDuringSchedulingIgnoredDuringExecution pod anti-affinity terms, lazily initializing
+// Affinity and PodAntiAffinity if they have not yet been set.
+func WithRequiredPodAntiAffinity(term corev1.PodAffinityTerm) Modification {
+	return func(podTemplateSpec *corev1.PodTemplateSpec) {
+		podAntiAffinity := ensurePodAntiAffinity(podTemplateSpec)
+		podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+			podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			term,
+		)
+	}
+}
+
+func ensurePodAntiAffinity(podTemplateSpec *corev1.PodTemplateSpec) *corev1.PodAntiAffinity {
+	if podTemplateSpec.Spec.Affinity == nil {
+		podTemplateSpec.Spec.Affinity = &corev1.Affinity{}
+	}
+	if podTemplateSpec.Spec.Affinity.PodAntiAffinity == nil {
+		podTemplateSpec.Spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	return podTemplateSpec.Spec.Affinity.PodAntiAffinity
+}
+
 func WithNodeAffinity(nodeAffinity *corev1.NodeAffinity) Modification {
 	return func(podTemplateSpec *corev1.PodTemplateSpec) {
 		podTemplateSpec.Spec.Affinity.NodeAffinity = nodeAffinity
@@ -181,6 +234,122 @@ func WithTolerations(tolerations []corev1.Toleration) Modification {
 	}
 }
 
+// WithStrategicMergeOverlay applies overlay onto the operator-built PodTemplateSpec using the
+// same strategic-merge-patch semantics `kubectl patch` uses: containers merge by `name`, volumes
+// by `name`, env by `name`, and volume mounts by `mountPath`. Tolerations are merged as a set
+// separately, since corev1.Toleration carries no patch merge key for StrategicMergePatch to act
+// on. This lets a user-supplied PodTemplateSpec override or extend individual fields without
+// clobbering the operator-managed containers, mounts and probes that a wholesale field overwrite
+// would lose, and without an overlay that simply omits a field (e.g. Containers) wiping it out.
+func WithStrategicMergeOverlay(overlay corev1.PodTemplateSpec) Modification {
+	return func(podTemplateSpec *corev1.PodTemplateSpec) {
+		merged, err := strategicMergePodTemplateSpec(*podTemplateSpec, overlay)
+		if err != nil {
+			// The base and overlay are both corev1.PodTemplateSpec values, so marshalling and
+			// patching against their own schema should never fail; if it does, the base is left
+			// untouched and the overlay is not applied.
+			return
+		}
+		*podTemplateSpec = merged
+	}
+}
+
+func strategicMergePodTemplateSpec(base, overlay corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	baseJSON, err := json.Marshal(base)
+	if err != nil {
+		return base, fmt.Errorf("error marshalling base PodTemplateSpec: %w", err)
+	}
+
+	overlayJSON, err := json.Marshal(overlay)
+	if err != nil {
+		return base, fmt.Errorf("error marshalling overlay PodTemplateSpec: %w", err)
+	}
+
+	overlayJSON, err = pruneUnsetMergeKeyedFields(overlay, overlayJSON)
+	if err != nil {
+		return base, fmt.Errorf("error preparing overlay PodTemplateSpec patch: %w", err)
+	}
+
+	patchedJSON, err := strategicpatch.StrategicMergePatch(baseJSON, overlayJSON, corev1.PodTemplateSpec{})
+	if err != nil {
+		return base, fmt.Errorf("error applying strategic merge patch: %w", err)
+	}
+
+	merged := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patchedJSON, &merged); err != nil {
+		return base, fmt.Errorf("error unmarshalling merged PodTemplateSpec: %w", err)
+	}
+
+	merged.Spec.Tolerations = mergeTolerationSet(base.Spec.Tolerations, overlay.Spec.Tolerations)
+
+	return merged, nil
+}
+
+// pruneUnsetMergeKeyedFields strips fields from the overlay's JSON patch that the overlay didn't
+// actually set but that corev1.PodSpec marshals as an explicit `null` rather than omitting,
+// because they carry no `omitempty` tag (Containers). StrategicMergePatch treats an
+// explicit `null` as "delete this field", so left unpruned an overlay that e.g. only sets
+// Tolerations would wipe every operator-managed container out of the base. Tolerations are
+// always pruned here; they have no patch merge key, so they're unioned separately in
+// mergeTolerationSet once the rest of the patch has been applied.
+func pruneUnsetMergeKeyedFields(overlay corev1.PodTemplateSpec, overlayJSON []byte) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(overlayJSON, &doc); err != nil {
+		return nil, err
+	}
+	specRaw, ok := doc["spec"]
+	if !ok {
+		return overlayJSON, nil
+	}
+
+	var spec map[string]json.RawMessage
+	if err := json.Unmarshal(specRaw, &spec); err != nil {
+		return nil, err
+	}
+
+	if len(overlay.Spec.Containers) == 0 {
+		delete(spec, "containers")
+	}
+	delete(spec, "tolerations")
+
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	doc["spec"] = specJSON
+
+	return json.Marshal(doc)
+}
+
+// mergeTolerationSet unions base and overlay tolerations, treating them as a set rather than the
+// wholesale replacement StrategicMergePatch would otherwise perform (corev1.Toleration has no
+// patch merge key). Duplicates carried over from base are dropped in favor of the overlay's copy.
+func mergeTolerationSet(base, overlay []corev1.Toleration) []corev1.Toleration {
+	merged := make([]corev1.Toleration, 0, len(base)+len(overlay))
+	for _, t := range overlay {
+		merged = append(merged, t)
+	}
+	for _, t := range base {
+		if containsToleration(merged, t) {
+			continue
+		}
+		merged = append(merged, t)
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+func containsToleration(tolerations []corev1.Toleration, t corev1.Toleration) bool {
+	for _, existing := range tolerations {
+		if reflect.DeepEqual(existing, t) {
+			return true
+		}
+	}
+	return false
+}
+
 func WithAnnotations(annotations map[string]string) Modification {
 	if annotations == nil {
 		annotations = map[string]string{}
@@ -189,3 +358,41 @@ func WithAnnotations(annotations map[string]string) Modification {
 		podTemplateSpec.Annotations = annotations
 	}
 }
+
+// WithTopologySpreadConstraints merges the provided constraints into the PodTemplateSpec,
+// keyed by TopologyKey: a constraint sharing a TopologyKey with an existing one replaces it,
+// otherwise it is appended.
+func WithTopologySpreadConstraints(constraints []corev1.TopologySpreadConstraint) Modification {
+	return func(podTemplateSpec *corev1.PodTemplateSpec) {
+		for _, c := range constraints {
+			idx := findTopologySpreadConstraintIndex(c.TopologyKey, podTemplateSpec.Spec.TopologySpreadConstraints)
+			if idx == notFound {
+				podTemplateSpec.Spec.TopologySpreadConstraints = append(podTemplateSpec.Spec.TopologySpreadConstraints, c)
+				continue
+			}
+			podTemplateSpec.Spec.TopologySpreadConstraints[idx] = c
+		}
+	}
+}
+
+// SpreadAcross returns a Modification that adds a single TopologySpreadConstraint built from
+// the given parameters, merging with any existing constraint for the same topologyKey.
+func SpreadAcross(topologyKey string, maxSkew int32, whenUnsatisfiable corev1.UnsatisfiableConstraintAction, labelSelector *metav1.LabelSelector) Modification {
+	return WithTopologySpreadConstraints([]corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           maxSkew,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     labelSelector,
+		},
+	})
+}
+
+func findTopologySpreadConstraintIndex(topologyKey string, constraints []corev1.TopologySpreadConstraint) int {
+	for idx, c := range constraints {
+		if c.TopologyKey == topologyKey {
+			return idx
+		}
+	}
+	return notFound
+}
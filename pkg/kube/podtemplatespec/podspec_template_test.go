@@ -0,0 +1,200 @@
+package podtemplatespec
+
+import (
+	"testing"
+
+	"github.com/mongodb/mongodb-kubernetes-operator/pkg/kube/podtemplatespec/container"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWithPreferredPodAntiAffinity_LazilyInitializesAffinity(t *testing.T) {
+	podTemplate := corev1.PodTemplateSpec{}
+
+	WithPreferredPodAntiAffinity(10, corev1.PodAffinityTerm{TopologyKey: "zone"})(&podTemplate)
+
+	terms := podTemplate.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 1)
+	assert.Equal(t, int32(10), terms[0].Weight)
+	assert.Equal(t, "zone", terms[0].PodAffinityTerm.TopologyKey)
+}
+
+func TestWithPreferredPodAntiAffinity_AppendsWithoutClobberingExistingTerm(t *testing.T) {
+	podTemplate := New(WithPreferredPodAntiAffinity(10, corev1.PodAffinityTerm{TopologyKey: "zone"}))
+
+	WithPreferredPodAntiAffinity(20, corev1.PodAffinityTerm{TopologyKey: "rack"})(&podTemplate)
+
+	terms := podTemplate.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 2)
+	assert.Equal(t, "zone", terms[0].PodAffinityTerm.TopologyKey)
+	assert.Equal(t, "rack", terms[1].PodAffinityTerm.TopologyKey)
+}
+
+func TestWithRequiredPodAntiAffinity_LazilyInitializesAffinity(t *testing.T) {
+	podTemplate := corev1.PodTemplateSpec{}
+
+	WithRequiredPodAntiAffinity(corev1.PodAffinityTerm{TopologyKey: "zone"})(&podTemplate)
+
+	terms := podTemplate.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 1)
+	assert.Equal(t, "zone", terms[0].TopologyKey)
+}
+
+func TestWithRequiredPodAntiAffinity_AppendsWithoutClobberingExistingTerm(t *testing.T) {
+	podTemplate := New(WithRequiredPodAntiAffinity(corev1.PodAffinityTerm{TopologyKey: "zone"}))
+
+	WithRequiredPodAntiAffinity(corev1.PodAffinityTerm{TopologyKey: "rack"})(&podTemplate)
+
+	terms := podTemplate.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 2)
+	assert.Equal(t, "zone", terms[0].TopologyKey)
+	assert.Equal(t, "rack", terms[1].TopologyKey)
+}
+
+func TestWithPreferredAndRequiredPodAntiAffinity_ShareLazilyInitializedPodAntiAffinity(t *testing.T) {
+	podTemplate := corev1.PodTemplateSpec{}
+
+	WithPreferredPodAntiAffinity(10, corev1.PodAffinityTerm{TopologyKey: "zone"})(&podTemplate)
+	WithRequiredPodAntiAffinity(corev1.PodAffinityTerm{TopologyKey: "rack"})(&podTemplate)
+
+	podAntiAffinity := podTemplate.Spec.Affinity.PodAntiAffinity
+	assert.Len(t, podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+	assert.Len(t, podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+}
+
+func TestWithTopologySpreadConstraints_ReplacesByTopologyKey(t *testing.T) {
+	podTemplate := New(WithTopologySpreadConstraints([]corev1.TopologySpreadConstraint{
+		{TopologyKey: "zone", MaxSkew: 1, WhenUnsatisfiable: corev1.DoNotSchedule},
+	}))
+
+	WithTopologySpreadConstraints([]corev1.TopologySpreadConstraint{
+		{TopologyKey: "zone", MaxSkew: 2, WhenUnsatisfiable: corev1.ScheduleAnyway},
+	})(&podTemplate)
+
+	assert.Len(t, podTemplate.Spec.TopologySpreadConstraints, 1, "a constraint sharing a TopologyKey must replace, not append")
+	assert.Equal(t, int32(2), podTemplate.Spec.TopologySpreadConstraints[0].MaxSkew)
+	assert.Equal(t, corev1.ScheduleAnyway, podTemplate.Spec.TopologySpreadConstraints[0].WhenUnsatisfiable)
+}
+
+func TestWithTopologySpreadConstraints_AppendsDifferentTopologyKey(t *testing.T) {
+	podTemplate := New(WithTopologySpreadConstraints([]corev1.TopologySpreadConstraint{
+		{TopologyKey: "zone", MaxSkew: 1},
+	}))
+
+	WithTopologySpreadConstraints([]corev1.TopologySpreadConstraint{
+		{TopologyKey: "rack", MaxSkew: 1},
+	})(&podTemplate)
+
+	assert.Len(t, podTemplate.Spec.TopologySpreadConstraints, 2)
+	assert.Equal(t, "zone", podTemplate.Spec.TopologySpreadConstraints[0].TopologyKey)
+	assert.Equal(t, "rack", podTemplate.Spec.TopologySpreadConstraints[1].TopologyKey)
+}
+
+func TestSpreadAcross_BuildsConstraintAndMergesWithExisting(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-replica-set"}}
+
+	podTemplate := New(SpreadAcross("zone", 1, corev1.DoNotSchedule, selector))
+	SpreadAcross("zone", 3, corev1.ScheduleAnyway, selector)(&podTemplate)
+
+	assert.Len(t, podTemplate.Spec.TopologySpreadConstraints, 1)
+	constraint := podTemplate.Spec.TopologySpreadConstraints[0]
+	assert.Equal(t, "zone", constraint.TopologyKey)
+	assert.Equal(t, int32(3), constraint.MaxSkew)
+	assert.Equal(t, corev1.ScheduleAnyway, constraint.WhenUnsatisfiable)
+	assert.Equal(t, selector, constraint.LabelSelector)
+}
+
+func TestWithContainerModifications_MergesIdempotentlyOnRepeatedApply(t *testing.T) {
+	podTemplate := New(WithContainer("mongod", func(c *corev1.Container) {
+		c.Name = "mongod"
+		c.Image = "mongo:6.0"
+	}))
+
+	mod := WithContainerModifications("mongod",
+		container.WithEnvVar(corev1.EnvVar{Name: "AGENT_VERSION", Value: "1.0"}),
+		container.WithVolumeMount(corev1.VolumeMount{Name: "data", MountPath: "/data/db"}),
+	)
+
+	// Applying twice simulates two reconciles building the same PodTemplateSpec; it must not
+	// duplicate the env var or volume mount.
+	mod(&podTemplate)
+	mod(&podTemplate)
+
+	c := podTemplate.Spec.Containers[0]
+	assert.Len(t, c.Env, 1)
+	assert.Len(t, c.VolumeMounts, 1)
+	assert.Equal(t, "mongo:6.0", c.Image, "unrelated fields set before the modification must survive")
+}
+
+func TestWithStrategicMergeOverlay_PreservesBaseContainersWhenOverlayOmitsThem(t *testing.T) {
+	base := New(
+		WithContainer("mongod", func(c *corev1.Container) {
+			c.Name = "mongod"
+			c.Image = "mongo:6.0"
+		}),
+		WithContainer("agent", func(c *corev1.Container) {
+			c.Name = "agent"
+			c.Image = "agent:1.0"
+		}),
+	)
+
+	overlay := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Tolerations: []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+		},
+	}
+
+	result := base
+	WithStrategicMergeOverlay(overlay)(&result)
+
+	assert.Len(t, result.Spec.Containers, 2, "overlay that doesn't set containers must not delete the base's")
+	assert.Equal(t, "mongo:6.0", result.Spec.Containers[0].Image)
+	assert.Equal(t, "agent:1.0", result.Spec.Containers[1].Image)
+}
+
+func TestWithStrategicMergeOverlay_MergesContainersByName(t *testing.T) {
+	base := New(
+		WithContainer("mongod", func(c *corev1.Container) {
+			c.Name = "mongod"
+			c.Image = "mongo:6.0"
+		}),
+	)
+
+	overlay := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "mongod",
+				Image: "mongo:7.0",
+			}},
+		},
+	}
+
+	result := base
+	WithStrategicMergeOverlay(overlay)(&result)
+
+	assert.Len(t, result.Spec.Containers, 1)
+	assert.Equal(t, "mongo:7.0", result.Spec.Containers[0].Image)
+}
+
+func TestWithStrategicMergeOverlay_UnionsTolerationsAsASet(t *testing.T) {
+	base := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Tolerations: []corev1.Toleration{{Key: "base-taint", Operator: corev1.TolerationOpExists}},
+		},
+	}
+
+	overlay := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Tolerations: []corev1.Toleration{{Key: "overlay-taint", Operator: corev1.TolerationOpExists}},
+		},
+	}
+
+	result := base
+	WithStrategicMergeOverlay(overlay)(&result)
+
+	assert.ElementsMatch(t, []corev1.Toleration{
+		{Key: "base-taint", Operator: corev1.TolerationOpExists},
+		{Key: "overlay-taint", Operator: corev1.TolerationOpExists},
+	}, result.Spec.Tolerations)
+}
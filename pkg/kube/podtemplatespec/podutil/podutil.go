@@ -0,0 +1,90 @@
+package podutil
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var podPhaseToOrdinal = map[corev1.PodPhase]int{
+	corev1.PodPending: 0,
+	corev1.PodUnknown: 1,
+	corev1.PodRunning: 2,
+}
+
+// SortByReadinessAndAge sorts pods in place such that the pods least safe to disrupt sort last:
+// unassigned pods first, then Pending, then Unknown, then Running; within a phase, not-ready
+// pods sort before ready ones; among ready pods, the one that has been ready the longest
+// (earliest PodReady LastTransitionTime) sorts last so it is preserved over pods that only
+// just became ready. Remaining ties are broken by container restart count (higher first),
+// presence of a deletion timestamp (set first), creation timestamp (newer first), then name.
+func SortByReadinessAndAge(pods []corev1.Pod) {
+	sort.Slice(pods, func(i, j int) bool {
+		return lessReadyAndAge(pods[i], pods[j])
+	})
+}
+
+func lessReadyAndAge(a, b corev1.Pod) bool {
+	if (len(a.Spec.NodeName) == 0) != (len(b.Spec.NodeName) == 0) {
+		return len(a.Spec.NodeName) == 0
+	}
+
+	if podPhaseToOrdinal[a.Status.Phase] != podPhaseToOrdinal[b.Status.Phase] {
+		return podPhaseToOrdinal[a.Status.Phase] < podPhaseToOrdinal[b.Status.Phase]
+	}
+
+	aReady, bReady := isPodReady(a), isPodReady(b)
+	if aReady != bReady {
+		return !aReady
+	}
+
+	if aReady && bReady {
+		aTime, bTime := podReadyTransitionTime(a), podReadyTransitionTime(b)
+		if !aTime.Equal(&bTime) {
+			return bTime.Before(&aTime)
+		}
+	}
+
+	if aRestarts, bRestarts := maxContainerRestarts(a), maxContainerRestarts(b); aRestarts != bRestarts {
+		return aRestarts > bRestarts
+	}
+
+	if aDeleting, bDeleting := a.DeletionTimestamp != nil, b.DeletionTimestamp != nil; aDeleting != bDeleting {
+		return aDeleting
+	}
+
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return b.CreationTimestamp.Before(&a.CreationTimestamp)
+	}
+
+	return a.Name < b.Name
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func podReadyTransitionTime(pod corev1.Pod) metav1.Time {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.LastTransitionTime
+		}
+	}
+	return metav1.Time{}
+}
+
+func maxContainerRestarts(pod corev1.Pod) int32 {
+	var max int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.RestartCount > max {
+			max = cs.RestartCount
+		}
+	}
+	return max
+}
@@ -0,0 +1,48 @@
+package podutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func readyPod(name string, readySince time.Time) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(readySince),
+			}},
+		},
+	}
+}
+
+func TestSortByReadinessAndAge_AmongReadyPodsLeastStableSortsFirst(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	longReady := readyPod("long-ready", now.Add(-24*time.Hour))
+	justReady := readyPod("just-ready", now)
+
+	pods := []corev1.Pod{longReady, justReady}
+	SortByReadinessAndAge(pods)
+
+	assert.Equal(t, "just-ready", pods[0].Name, "the most recently ready (least stable) pod must sort first")
+	assert.Equal(t, "long-ready", pods[1].Name, "the longest-ready (most stable) pod must sort last, to be preserved")
+}
+
+func TestSortByReadinessAndAge_UnassignedBeforePendingBeforeRunning(t *testing.T) {
+	unassigned := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "unassigned"}}
+	pending := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pending"}, Spec: corev1.PodSpec{NodeName: "node-1"}, Status: corev1.PodStatus{Phase: corev1.PodPending}}
+	running := readyPod("running", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	pods := []corev1.Pod{running, pending, unassigned}
+	SortByReadinessAndAge(pods)
+
+	assert.Equal(t, []string{"unassigned", "pending", "running"}, []string{pods[0].Name, pods[1].Name, pods[2].Name})
+}
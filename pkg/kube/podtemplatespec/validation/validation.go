@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AntiAffinityError is returned when a required pod anti-affinity term on a PodTemplateSpec
+// cannot be satisfied by the available nodes for the requested replica count.
+type AntiAffinityError struct {
+	Term             corev1.PodAffinityTerm
+	RequiredDomains  int
+	AvailableDomains int
+}
+
+func (e *AntiAffinityError) Error() string {
+	return fmt.Sprintf(
+		"anti-affinity term with topology key %q requires %d unoccupied topology domains, but only %d are available across the node inventory",
+		e.Term.TopologyKey, e.RequiredDomains, e.AvailableDomains,
+	)
+}
+
+// ValidateAntiAffinityForReplicaCount walks the RequiredDuringSchedulingIgnoredDuringExecution pod
+// anti-affinity terms on podTemplate and, for each one, groups nodes by the term's TopologyKey
+// into topology domains, then checks that at least `members` of those domains are unoccupied: no
+// pod in pods, in the namespaces the term selects, with labels matching the term's LabelSelector,
+// already sits on a node sharing that domain's topology-key value. This mirrors the scheduler's
+// own anti-affinity semantics, rather than the weaker "enough distinct zones exist somewhere"
+// check a plain domain count gives you, which reports success even when every zone is already
+// saturated with matching pods.
+//
+// namespace is the namespace new replica-set members would be created in; it stands in for the
+// term's own namespace when neither Namespaces nor NamespaceSelector is set, matching the
+// scheduler's "defaults to the pod's own namespace" behavior. A non-empty NamespaceSelector would
+// need the labels of the actual Namespace objects to resolve, which this helper has no access to;
+// such terms conservatively fall back to matching only namespace, same as the unset case. An empty
+// NamespaceSelector (selecting every namespace) is still handled precisely, since it needs no
+// Namespace lookups.
+func ValidateAntiAffinityForReplicaCount(podTemplate corev1.PodTemplateSpec, members int, nodes []corev1.Node, pods []corev1.Pod, namespace string) error {
+	affinity := podTemplate.Spec.Affinity
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		return nil
+	}
+
+	for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+		if err != nil {
+			return fmt.Errorf("invalid label selector on anti-affinity term with topology key %q: %w", term.TopologyKey, err)
+		}
+
+		occupiedDomains := make(map[string]struct{})
+		for _, pod := range pods {
+			if !termMatchesNamespace(term, pod.Namespace, namespace) {
+				continue
+			}
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			if domain, ok := nodeDomainFor(pod.Spec.NodeName, term.TopologyKey, nodes); ok {
+				occupiedDomains[domain] = struct{}{}
+			}
+		}
+
+		available := 0
+		for domain := range distinctTopologyDomains(term.TopologyKey, nodes) {
+			if _, occupied := occupiedDomains[domain]; !occupied {
+				available++
+			}
+		}
+
+		if available < members {
+			return &AntiAffinityError{
+				Term:             term,
+				RequiredDomains:  members,
+				AvailableDomains: available,
+			}
+		}
+	}
+
+	return nil
+}
+
+// termMatchesNamespace reports whether podNamespace falls within the namespaces term selects,
+// defaulting to ownNamespace per the scheduler's own-namespace default.
+func termMatchesNamespace(term corev1.PodAffinityTerm, podNamespace, ownNamespace string) bool {
+	if term.NamespaceSelector != nil {
+		if len(term.NamespaceSelector.MatchLabels) == 0 && len(term.NamespaceSelector.MatchExpressions) == 0 {
+			return true
+		}
+		return podNamespace == ownNamespace
+	}
+
+	if len(term.Namespaces) > 0 {
+		for _, ns := range term.Namespaces {
+			if ns == podNamespace {
+				return true
+			}
+		}
+		return false
+	}
+
+	return podNamespace == ownNamespace
+}
+
+func nodeDomainFor(nodeName, topologyKey string, nodes []corev1.Node) (string, bool) {
+	for _, node := range nodes {
+		if node.Name != nodeName {
+			continue
+		}
+		value, ok := node.Labels[topologyKey]
+		return value, ok
+	}
+	return "", false
+}
+
+func distinctTopologyDomains(topologyKey string, nodes []corev1.Node) map[string]struct{} {
+	domains := make(map[string]struct{})
+	for _, node := range nodes {
+		if value, ok := node.Labels[topologyKey]; ok {
+			domains[value] = struct{}{}
+		}
+	}
+	return domains
+}
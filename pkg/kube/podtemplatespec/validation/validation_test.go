@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func podTemplateWithRequiredAntiAffinity(topologyKey string, labelSelector *metav1.LabelSelector) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Affinity: &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+						TopologyKey:   topologyKey,
+						LabelSelector: labelSelector,
+					}},
+				},
+			},
+		},
+	}
+}
+
+func nodeIn(name, zone string) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"topology.kubernetes.io/zone": zone}},
+	}
+}
+
+func memberPod(name, namespace, node string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": "my-replica-set"}},
+		Spec:       corev1.PodSpec{NodeName: node},
+	}
+}
+
+func TestValidateAntiAffinityForReplicaCount_FailsWhenMatchingPodsSaturateEveryZone(t *testing.T) {
+	podTemplate := podTemplateWithRequiredAntiAffinity(
+		"topology.kubernetes.io/zone",
+		&metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-replica-set"}},
+	)
+	nodes := []corev1.Node{nodeIn("node-a", "zone-a"), nodeIn("node-b", "zone-b"), nodeIn("node-c", "zone-c")}
+	pods := []corev1.Pod{
+		memberPod("my-replica-set-0", "ns", "node-a"),
+		memberPod("my-replica-set-1", "ns", "node-b"),
+		memberPod("my-replica-set-2", "ns", "node-c"),
+	}
+
+	// Three distinct zones exist, but all three are already occupied by matching pods, so a
+	// fourth member (or even a third being rescheduled) has nowhere left to go.
+	err := ValidateAntiAffinityForReplicaCount(podTemplate, 1, nodes, pods, "ns")
+
+	var antiAffinityErr *AntiAffinityError
+	assert.ErrorAs(t, err, &antiAffinityErr)
+	assert.Equal(t, 0, antiAffinityErr.AvailableDomains)
+}
+
+func TestValidateAntiAffinityForReplicaCount_SucceedsWhenEnoughZonesAreUnoccupied(t *testing.T) {
+	podTemplate := podTemplateWithRequiredAntiAffinity(
+		"topology.kubernetes.io/zone",
+		&metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-replica-set"}},
+	)
+	nodes := []corev1.Node{nodeIn("node-a", "zone-a"), nodeIn("node-b", "zone-b"), nodeIn("node-c", "zone-c")}
+	pods := []corev1.Pod{memberPod("my-replica-set-0", "ns", "node-a")}
+
+	err := ValidateAntiAffinityForReplicaCount(podTemplate, 2, nodes, pods, "ns")
+
+	assert.NoError(t, err)
+}
+
+func TestValidateAntiAffinityForReplicaCount_IgnoresPodsInOtherNamespaces(t *testing.T) {
+	podTemplate := podTemplateWithRequiredAntiAffinity(
+		"topology.kubernetes.io/zone",
+		&metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-replica-set"}},
+	)
+	nodes := []corev1.Node{nodeIn("node-a", "zone-a")}
+	pods := []corev1.Pod{memberPod("my-replica-set-0", "other-ns", "node-a")}
+
+	err := ValidateAntiAffinityForReplicaCount(podTemplate, 1, nodes, pods, "ns")
+
+	assert.NoError(t, err, "a matching pod in an unrelated namespace must not count as occupying the zone")
+}